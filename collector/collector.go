@@ -0,0 +1,261 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aokumasan/nifcloud-sdk-go-v2/nifcloud"
+	"github.com/aokumasan/nifcloud-sdk-go-v2/service/nas"
+	"github.com/aws/aws-sdk-go-v2/private/protocol/query/queryutil"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace       = "nifcloud_nas"
+	timestampLayout = "2006-01-02 15:04:05"
+)
+
+var (
+	scrapeDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "scrape", "collector_duration_seconds"),
+		"nifcloud_nas_exporter: Duration of a collector scrape.",
+		[]string{"instance", "region", "metric_name"},
+		nil,
+	)
+	scrapeSuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "scrape", "collector_success"),
+		"nifcloud_nas_exporter: Whether a collector succeeded.",
+		[]string{"instance", "region", "metric_name"},
+		nil,
+	)
+	metricTimestampDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "metric_timestamp_seconds"),
+		"nifcloud_nas_exporter: Timestamp of the last datapoint cached for a metric.",
+		[]string{"instance", "region", "metric_name"},
+		nil,
+	)
+
+	label = []string{"instance", "region"}
+)
+
+// Metric describes a single NIFCLOUD NAS CloudWatch metric.
+type Metric struct {
+	Name     string
+	FlagName string
+	Desc     *prometheus.Desc
+	Default  bool
+}
+
+// datapoint is the latest polled value cached for a metric.
+type datapoint struct {
+	value     float64
+	timestamp time.Time
+	duration  time.Duration
+	success   bool
+}
+
+// NASCollector collects the enabled metrics for a single NAS instance.
+type NASCollector struct {
+	client                *nas.Client
+	metrics               []Metric
+	nasInstanceIdentifier string
+	region                string
+	logger                *slog.Logger
+
+	mu    sync.RWMutex
+	cache map[string]datapoint
+
+	stopCh chan struct{}
+}
+
+// NewNASCollector creates a NASCollector for the given NAS instance. It
+// blocks until every metric has been fetched once, then polls each on its
+// own ticker at --nifcloud.polling-interval.
+func NewNASCollector(nasInstanceIdentifier, accessKeyID, secretAccessKey, region string, logger *slog.Logger) *NASCollector {
+	n := &NASCollector{
+		client:                nas.New(nifcloud.NewConfig(accessKeyID, secretAccessKey, region)),
+		metrics:               EnabledMetrics(),
+		nasInstanceIdentifier: nasInstanceIdentifier,
+		region:                region,
+		logger:                logger,
+		cache:                 map[string]datapoint{},
+		stopCh:                make(chan struct{}),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(n.metrics))
+	for _, m := range n.metrics {
+		go func(m Metric) {
+			n.poll(m)
+			wg.Done()
+		}(m)
+	}
+	wg.Wait()
+
+	for _, m := range n.metrics {
+		go n.pollMetric(m)
+	}
+
+	return n
+}
+
+// Close stops this collector's background pollers.
+func (n *NASCollector) Close() {
+	close(n.stopCh)
+}
+
+func (n *NASCollector) pollMetric(metric Metric) {
+	ticker := time.NewTicker(*pollingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n.poll(metric)
+		case <-n.stopCh:
+			return
+		}
+	}
+}
+
+func (n *NASCollector) poll(metric Metric) {
+	begin := time.Now()
+	value, timestamp, err := n.fetch(metric)
+	duration := time.Since(begin)
+
+	if err != nil {
+		n.logger.Error("scrape failed",
+			"instance", n.nasInstanceIdentifier,
+			"region", n.region,
+			"metric", metric.Name,
+			"duration_ms", duration.Milliseconds(),
+			"err", err,
+		)
+	}
+
+	n.mu.Lock()
+	cached := n.cache[metric.Name]
+	cached.duration = duration
+	cached.success = err == nil
+	if err == nil {
+		cached.value = value
+		cached.timestamp = timestamp
+	}
+	n.cache[metric.Name] = cached
+	n.mu.Unlock()
+}
+
+func (n *NASCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range n.metrics {
+		ch <- m.Desc
+	}
+
+	ch <- scrapeDurationDesc
+	ch <- scrapeSuccessDesc
+	ch <- metricTimestampDesc
+}
+
+func (n *NASCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range n.metrics {
+		n.mu.RLock()
+		cached, ok := n.cache[m.Name]
+		n.mu.RUnlock()
+
+		success := 0.0
+		if ok && cached.success {
+			success = 1
+			ch <- prometheus.MustNewConstMetric(m.Desc, prometheus.GaugeValue, cached.value, n.nasInstanceIdentifier, n.region)
+			ch <- prometheus.MustNewConstMetric(metricTimestampDesc, prometheus.GaugeValue, float64(cached.timestamp.Unix()), n.nasInstanceIdentifier, n.region, m.Name)
+		}
+
+		ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, cached.duration.Seconds(), n.nasInstanceIdentifier, n.region, m.Name)
+		ch <- prometheus.MustNewConstMetric(scrapeSuccessDesc, prometheus.GaugeValue, success, n.nasInstanceIdentifier, n.region, m.Name)
+	}
+}
+
+// fetch issues a single GetMetricStatistics-style call over --nifcloud.period.
+func (n *NASCollector) fetch(metric Metric) (float64, time.Time, error) {
+	now := time.Now().In(time.UTC)
+	request := n.client.GetMetricStatisticsRequest(&nas.GetMetricStatisticsInput{
+		Dimensions: []nas.RequestDimensionsStruct{
+			{
+				Name:  nifcloud.String("NASInstanceIdentifier"),
+				Value: nifcloud.String(n.nasInstanceIdentifier),
+			},
+		},
+		MetricName: nifcloud.String(metric.Name),
+	})
+	if err := request.Build(); err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed building request: %v", err)
+	}
+	body := url.Values{
+		"Action":  {request.Operation.Name},
+		"Version": {request.Metadata.APIVersion},
+	}
+	if err := queryutil.Parse(body, request.Params, false); err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed encoding request: %v", err)
+	}
+	body.Set("StartTime", now.Add(-*period).Format(timestampLayout))
+	body.Set("EndTime", now.Format(timestampLayout))
+	request.SetBufferBody([]byte(body.Encode()))
+
+	response, err := request.Send(context.Background())
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	datapoints := response.Datapoints
+	if len(datapoints) == 0 {
+		return 0, time.Time{}, errors.New("fetched no datapoints")
+	}
+
+	var latest time.Time
+	var latestVal float64
+	for _, dp := range datapoints {
+		timestamp, err := time.Parse(time.RFC3339, nifcloud.StringValue(dp.Timestamp))
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("could not parse timestamp %q: %v", nifcloud.StringValue(dp.Timestamp), err)
+		}
+
+		if timestamp.Before(latest) {
+			continue
+		}
+
+		raw, err := statisticValue(dp, *statistic)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+
+		val, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("could not parse %s %q: %v", *statistic, raw, err)
+		}
+
+		latest = timestamp
+		latestVal = val
+	}
+
+	return latestVal, latest, nil
+}
+
+// statisticValue picks the requested aggregate out of a datapoint.
+func statisticValue(dp nas.DatapointsStruct, statistic string) (string, error) {
+	switch statistic {
+	case "Sum":
+		return nifcloud.StringValue(dp.Sum), nil
+	case "Average":
+		return nifcloud.StringValue(dp.Average), nil
+	case "Maximum":
+		return nifcloud.StringValue(dp.Maximum), nil
+	case "Minimum":
+		return nifcloud.StringValue(dp.Minimum), nil
+	default:
+		return "", fmt.Errorf("unsupported statistic %q", statistic)
+	}
+}
@@ -0,0 +1,36 @@
+package collector
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// InstanceConfig describes a single NAS instance to scrape.
+type InstanceConfig struct {
+	NASInstanceIdentifier string `yaml:"nas_instance_id"`
+	Region                string `yaml:"region"`
+	AccessKeyID           string `yaml:"access_key_id"`
+	SecretAccessKey       string `yaml:"secret_access_key"`
+}
+
+// Config is the top-level shape of a --nifcloud.config-file document.
+type Config struct {
+	Instances []InstanceConfig `yaml:"instances"`
+}
+
+// LoadConfig reads and parses a multi-instance config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading config file: %v", err)
+	}
+
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed parsing config file: %v", err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.yaml")
+	validYAML := `
+instances:
+  - nas_instance_id: nas1
+    region: jp-east-1
+    access_key_id: key1
+    secret_access_key: secret1
+  - nas_instance_id: nas2
+    region: jp-east-2
+    access_key_id: key2
+    secret_access_key: secret2
+`
+	if err := os.WriteFile(valid, []byte(validYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(valid)
+	if err != nil {
+		t.Fatalf("LoadConfig(%q) returned error: %v", valid, err)
+	}
+	if len(config.Instances) != 2 {
+		t.Fatalf("got %d instances, want 2", len(config.Instances))
+	}
+	want := InstanceConfig{
+		NASInstanceIdentifier: "nas1",
+		Region:                "jp-east-1",
+		AccessKeyID:           "key1",
+		SecretAccessKey:       "secret1",
+	}
+	if config.Instances[0] != want {
+		t.Errorf("first instance = %+v, want %+v", config.Instances[0], want)
+	}
+
+	malformed := filepath.Join(dir, "malformed.yaml")
+	if err := os.WriteFile(malformed, []byte("instances: [this is not valid yaml"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(malformed); err == nil {
+		t.Error("LoadConfig with malformed YAML: got nil error, want non-nil")
+	}
+
+	if _, err := LoadConfig(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("LoadConfig with missing file: got nil error, want non-nil")
+	}
+}
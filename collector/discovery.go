@@ -0,0 +1,40 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aokumasan/nifcloud-sdk-go-v2/nifcloud"
+	"github.com/aokumasan/nifcloud-sdk-go-v2/service/nas"
+)
+
+// Discoverer enumerates the NAS instances available in a region.
+type Discoverer struct {
+	client *nas.Client
+	region string
+}
+
+// NewDiscoverer creates a Discoverer for the given region.
+func NewDiscoverer(accessKeyID, secretAccessKey, region string) *Discoverer {
+	return &Discoverer{
+		client: nas.New(nifcloud.NewConfig(accessKeyID, secretAccessKey, region)),
+		region: region,
+	}
+}
+
+// Discover returns the identifiers of every NAS instance in the region.
+func (d *Discoverer) Discover(ctx context.Context) ([]string, error) {
+	request := d.client.DescribeNASInstancesRequest(&nas.DescribeNASInstancesInput{})
+
+	response, err := request.Send(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed describing nas instances: %v", err)
+	}
+
+	identifiers := make([]string, 0, len(response.NASInstances))
+	for _, instance := range response.NASInstances {
+		identifiers = append(identifiers, nifcloud.StringValue(instance.NASInstanceIdentifier))
+	}
+
+	return identifiers, nil
+}
@@ -0,0 +1,117 @@
+package collector
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// allMetrics is the full catalogue of metrics the exporter knows how to scrape.
+var allMetrics = []Metric{
+	{
+		Name:     "FreeStorageSpace",
+		FlagName: "free_storage_space",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "free_storage_space"),
+			"The amount of available storage space. Units: Bytes",
+			label, nil,
+		),
+	},
+	{
+		Name:     "UsedStorageSpace",
+		FlagName: "used_storage_space",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "used_storage_space"),
+			"The amount of used storage space. Units: Bytes",
+			label, nil,
+		),
+	},
+	{
+		Name:     "ReadIOPS",
+		FlagName: "read_iops",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "read_iops"),
+			"The average number of disk read I/O operations per second. Units: Count/Second",
+			label, nil,
+		),
+	},
+	{
+		Name:     "WriteIOPS",
+		FlagName: "write_iops",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "write_iops"),
+			"The average number of disk write I/O operations per second. Units: Count/Second",
+			label, nil,
+		),
+	},
+	{
+		Name:     "ReadThroughput",
+		FlagName: "read_throughput",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "read_throughput"),
+			"The average number of bytes read from disk per second. Units: Bytes/Second",
+			label, nil,
+		),
+	},
+	{
+		Name:     "WriteThroughput",
+		FlagName: "write_throughput",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "write_throughput"),
+			"The average number of bytes written to disk per second. Units: Bytes/Second",
+			label, nil,
+		),
+	},
+	{
+		Name:     "ActiveConnections",
+		FlagName: "active_connections",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "active_connections"),
+			"The active connection counts. Units: Count",
+			label, nil,
+		),
+	},
+	{
+		Name:     "GlobalReadTraffic",
+		FlagName: "global_read_traffic",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "global_read_traffic"),
+			"The incoming (Receive) network traffic from global on the NAS instance. Units: Bytes/second",
+			label, nil,
+		),
+	},
+	{
+		Name:     "PrivateReadTraffic",
+		FlagName: "private_read_traffic",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "private_read_traffic"),
+			"The incoming (Receive) network traffic from private on the NAS instance. Units: Bytes/second",
+			label, nil,
+		),
+	},
+	{
+		Name:     "GlobalWriteTraffic",
+		FlagName: "global_write_traffic",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "global_write_traffic"),
+			"The outgoing (Transmit) network traffic to global on the NAS instance. Units: Bytes/second",
+			label, nil,
+		),
+	},
+	{
+		Name:     "PrivateWriteTraffic",
+		FlagName: "private_write_traffic",
+		Default:  true,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "private_write_traffic"),
+			"The outgoing (Transmit) network traffic to private on the NAS instance. Units: Bytes/second",
+			label, nil,
+		),
+	},
+}
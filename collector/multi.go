@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MultiCollector fans a single Prometheus scrape out across many
+// NASCollectors, one per NAS instance. Its set of instances can be replaced
+// at runtime via SetInstances.
+type MultiCollector struct {
+	logger *slog.Logger
+
+	mu         sync.RWMutex
+	collectors map[string]*NASCollector
+}
+
+// NewMultiCollector creates a MultiCollector with no instances. Use
+// SetInstances to populate it.
+func NewMultiCollector(logger *slog.Logger) *MultiCollector {
+	return &MultiCollector{
+		logger:     logger,
+		collectors: map[string]*NASCollector{},
+	}
+}
+
+// newCollector builds the collector for a newly-seen instance; overridden in
+// tests to avoid making a live API call.
+var newCollector = NewNASCollector
+
+// SetInstances replaces the set of NAS instances being collected. Existing
+// instances keep their collector (and its warm poller cache); instances no
+// longer present have their collector closed; new ones get a fresh one.
+//
+// New collectors are built (and thus make their first, blocking API call)
+// before the lock is taken, so a discovery-driven refresh never stalls a
+// Collect/Describe call already in flight.
+func (m *MultiCollector) SetInstances(instances []InstanceConfig) {
+	m.mu.RLock()
+	existing := m.collectors
+	m.mu.RUnlock()
+
+	next := make(map[string]*NASCollector, len(instances))
+	for _, instance := range instances {
+		if c, ok := existing[instance.NASInstanceIdentifier]; ok {
+			next[instance.NASInstanceIdentifier] = c
+			continue
+		}
+
+		next[instance.NASInstanceIdentifier] = newCollector(
+			instance.NASInstanceIdentifier, instance.AccessKeyID, instance.SecretAccessKey, instance.Region, m.logger,
+		)
+	}
+
+	m.mu.Lock()
+	stale := m.collectors
+	m.collectors = next
+	m.mu.Unlock()
+
+	for id, c := range stale {
+		if next[id] != c {
+			c.Close()
+		}
+	}
+}
+
+func (m *MultiCollector) Describe(ch chan<- *prometheus.Desc) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, c := range m.collectors {
+		c.Describe(ch)
+	}
+}
+
+func (m *MultiCollector) Collect(ch chan<- prometheus.Metric) {
+	m.mu.RLock()
+	collectors := make([]*NASCollector, 0, len(m.collectors))
+	for _, c := range m.collectors {
+		collectors = append(collectors, c)
+	}
+	m.mu.RUnlock()
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(collectors))
+	for _, c := range collectors {
+		go func(c *NASCollector) {
+			c.Collect(ch)
+			wg.Done()
+		}(c)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"testing"
+)
+
+func fakeCollector(id string) *NASCollector {
+	return &NASCollector{
+		nasInstanceIdentifier: id,
+		cache:                 map[string]datapoint{},
+		stopCh:                make(chan struct{}),
+	}
+}
+
+func isClosed(c *NASCollector) bool {
+	select {
+	case <-c.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestMultiCollectorSetInstances(t *testing.T) {
+	origNewCollector := newCollector
+	defer func() { newCollector = origNewCollector }()
+
+	var created int32
+	newCollector = func(nasInstanceIdentifier, accessKeyID, secretAccessKey, region string, logger *slog.Logger) *NASCollector {
+		atomic.AddInt32(&created, 1)
+		return fakeCollector(nasInstanceIdentifier)
+	}
+
+	m := NewMultiCollector(nil)
+
+	m.SetInstances([]InstanceConfig{{NASInstanceIdentifier: "a"}, {NASInstanceIdentifier: "b"}})
+	if got := atomic.LoadInt32(&created); got != 2 {
+		t.Fatalf("created = %d, want 2", got)
+	}
+	a, b := m.collectors["a"], m.collectors["b"]
+	if a == nil || b == nil {
+		t.Fatalf("expected collectors for both a and b, got %v", m.collectors)
+	}
+
+	// Re-applying the same set must keep the existing collectors, untouched.
+	m.SetInstances([]InstanceConfig{{NASInstanceIdentifier: "a"}, {NASInstanceIdentifier: "b"}})
+	if got := atomic.LoadInt32(&created); got != 2 {
+		t.Fatalf("created = %d after re-applying the same set, want 2 (no new collectors)", got)
+	}
+	if m.collectors["a"] != a || m.collectors["b"] != b {
+		t.Fatalf("SetInstances replaced a collector that was still configured")
+	}
+	if isClosed(a) || isClosed(b) {
+		t.Fatalf("SetInstances closed a collector that is still configured")
+	}
+
+	// Dropping "b" must close and remove its collector, leaving "a" alone.
+	m.SetInstances([]InstanceConfig{{NASInstanceIdentifier: "a"}})
+	if _, ok := m.collectors["b"]; ok {
+		t.Fatalf("b should have been removed from collectors")
+	}
+	if !isClosed(b) {
+		t.Fatalf("dropped collector b was not closed")
+	}
+	if m.collectors["a"] != a || isClosed(a) {
+		t.Fatalf("unrelated collector a should be untouched")
+	}
+
+	// Re-adding "b" must build it a fresh collector, not its stale closed one.
+	m.SetInstances([]InstanceConfig{{NASInstanceIdentifier: "a"}, {NASInstanceIdentifier: "b"}})
+	if got := atomic.LoadInt32(&created); got != 3 {
+		t.Fatalf("created = %d after re-adding b, want 3", got)
+	}
+	if newB := m.collectors["b"]; newB == b {
+		t.Fatalf("re-added instance b reused its stale, closed collector")
+	}
+}
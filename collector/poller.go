@@ -0,0 +1,22 @@
+package collector
+
+import (
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	pollingInterval = kingpin.Flag(
+		"nifcloud.polling-interval",
+		"How often to poll CloudWatch-style metrics in the background. Prometheus scrapes are served from the cached result.",
+	).Default("60s").Duration()
+
+	period = kingpin.Flag(
+		"nifcloud.period",
+		"Width of the GetMetricStatistics time window to request on each poll.",
+	).Default("3m").Duration()
+
+	statistic = kingpin.Flag(
+		"nifcloud.statistic",
+		"CloudWatch-style statistic to read from each datapoint (Sum, Average, Maximum or Minimum).",
+	).Default("Sum").Enum("Sum", "Average", "Maximum", "Minimum")
+)
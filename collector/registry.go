@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	collectorEnabled  = map[string]*bool{}
+	collectorSetByCLI = map[string]bool{}
+
+	disableDefaults = kingpin.Flag(
+		"collector.disable-defaults",
+		"Set all collectors to disabled by default, enabling only the ones explicitly passed on the command line.",
+	).Default("false").Bool()
+)
+
+func init() {
+	for _, m := range allMetrics {
+		registerMetricFlag(m)
+	}
+}
+
+// registerMetricFlag wires up a `--collector.<name>` / `--no-collector.<name>` flag pair.
+func registerMetricFlag(m Metric) {
+	state := "disabled"
+	if m.Default {
+		state = "enabled"
+	}
+
+	enabled := kingpin.Flag(
+		fmt.Sprintf("collector.%s", m.FlagName),
+		fmt.Sprintf("Enable the %s collector (default: %s).", m.Name, state),
+	).Default(strconv.FormatBool(m.Default)).Action(func(*kingpin.ParseContext) error {
+		collectorSetByCLI[m.Name] = true
+		return nil
+	}).Bool()
+
+	collectorEnabled[m.Name] = enabled
+}
+
+// EnabledMetrics returns the metrics selected by the `--collector.*` flags.
+func EnabledMetrics() []Metric {
+	enabled := make([]Metric, 0, len(allMetrics))
+	for _, m := range allMetrics {
+		state := *collectorEnabled[m.Name]
+		if *disableDefaults && !collectorSetByCLI[m.Name] {
+			state = false
+		}
+
+		if state {
+			enabled = append(enabled, m)
+		}
+	}
+
+	return enabled
+}
@@ -0,0 +1,50 @@
+package collector
+
+import "testing"
+
+func TestEnabledMetrics(t *testing.T) {
+	const metric = "FreeStorageSpace"
+
+	origEnabled := *collectorEnabled[metric]
+	origSetByCLI := collectorSetByCLI
+	origDisableDefaults := *disableDefaults
+	defer func() {
+		*collectorEnabled[metric] = origEnabled
+		collectorSetByCLI = origSetByCLI
+		*disableDefaults = origDisableDefaults
+	}()
+
+	tests := []struct {
+		name            string
+		disableDefaults bool
+		enabled         bool
+		setByCLI        bool
+		want            bool
+	}{
+		{"enabled by default, disable-defaults off", false, true, false, true},
+		{"disabled by default, disable-defaults off", false, false, false, false},
+		{"disable-defaults drops an unset default-enabled metric", true, true, false, false},
+		{"disable-defaults keeps a metric explicitly re-enabled on the CLI", true, true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*disableDefaults = tt.disableDefaults
+			*collectorEnabled[metric] = tt.enabled
+			collectorSetByCLI = map[string]bool{}
+			if tt.setByCLI {
+				collectorSetByCLI[metric] = true
+			}
+
+			got := false
+			for _, m := range EnabledMetrics() {
+				if m.Name == metric {
+					got = true
+				}
+			}
+			if got != tt.want {
+				t.Errorf("EnabledMetrics() includes %s = %v, want %v", metric, got, tt.want)
+			}
+		})
+	}
+}
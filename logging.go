@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// newLogger builds the process-wide structured logger from --log.level and
+// --log.format.
+func newLogger(level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLogLevel(level)}
+
+	var handler slog.Handler
+	switch format {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+
+	return slog.New(handler)
+}
+
+func parseLogLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// promErrorLogger adapts a *slog.Logger to the promhttp.Logger interface
+// (a single Println(...interface{}) method).
+type promErrorLogger struct {
+	logger *slog.Logger
+}
+
+func (l promErrorLogger) Println(v ...interface{}) {
+	l.logger.Error(fmt.Sprint(v...))
+}
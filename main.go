@@ -1,13 +1,20 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/aokumasan/nifcloud_nas_exporter/collector"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/prometheus/common/log"
 	"github.com/prometheus/common/version"
+	"github.com/prometheus/exporter-toolkit/web"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -31,57 +38,238 @@ func main() {
 			"web.max-requests",
 			"Maximum number of parallel scrape requests. Use 0 to disable.",
 		).Default("40").Int()
-		nasInstanceID = kingpin.Flag(
+		nasInstanceIDs = kingpin.Flag(
 			"nifcloud.nas-instance-id",
-			"Target NAS instance identifier.",
-		).Required().String()
+			"Target NAS instance identifier. Repeat to scrape several instances with the same credentials/region.",
+		).Strings()
 		region = kingpin.Flag(
 			"nifcloud.region",
-			"NIFCLOUD region name that target instance exists.",
+			"NIFCLOUD region name that target instance(s) exist in.",
 		).Default("jp-east-1").String()
 		accessKeyID = kingpin.Flag(
 			"nifcloud.access-key-id",
 			"NIFCLOUD Access Key ID to fetch the metrics.",
-		).Required().String()
+		).String()
 		secretAccessKey = kingpin.Flag(
 			"nifcloud.secret-access-key",
 			"NIFCLOUD Secret Access Key to fetch the metrics.",
-		).Required().String()
+		).String()
+		configFile = kingpin.Flag(
+			"nifcloud.config-file",
+			"Path to a YAML file listing NAS instances to scrape, with per-instance credentials/region.",
+		).String()
+		discovery = kingpin.Flag(
+			"nifcloud.discovery",
+			"Auto-discover NAS instances in --nifcloud.region via DescribeNASInstances instead of (or in addition to) listing them explicitly.",
+		).Bool()
+		discoveryInterval = kingpin.Flag(
+			"nifcloud.discovery-interval",
+			"How often to refresh the auto-discovered instance list.",
+		).Default("5m").Duration()
+		logLevel = kingpin.Flag(
+			"log.level",
+			"Only log messages with the given severity or above. One of: [debug, info, warn, error]",
+		).Default("info").Enum("debug", "info", "warn", "error")
+		logFormat = kingpin.Flag(
+			"log.format",
+			"Output format of log messages. One of: [logfmt, json]",
+		).Default("logfmt").Enum("logfmt", "json")
+		webConfigFile = kingpin.Flag(
+			"web.config.file",
+			"Path to a file enabling TLS and/or basic auth, in exporter-toolkit's web config format.",
+		).Default("").String()
 	)
 
-	log.AddFlags(kingpin.CommandLine)
 	kingpin.Version(version.Print(exporterName))
 	kingpin.HelpFlag.Short('h')
 	kingpin.Parse()
 
-	log.Infof("Starting %s %v", exporterName, version.Info())
-	log.Infoln("Build context", version.BuildContext())
+	logger := newLogger(*logLevel, *logFormat)
 
-	http.Handle(
-		*metricsPath,
-		newHandler(
-			!*disableExporterMetrics, *maxRequests,
-			*nasInstanceID, *accessKeyID, *secretAccessKey, *region,
-		),
-	)
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(`<html>
-			<head><title>NIFCLOUD NAS Exporter</title></head>
-			<body>
-			<h1>NIFCLOUD NAS Exporter</h1>
-			<p><a href="` + *metricsPath + `">Metrics</a></p>
-			</body>
-			</html>`))
+	logger.Info(fmt.Sprintf("Starting %s %s", exporterName, version.Info()))
+	logger.Info(fmt.Sprintf("Build context %s", version.BuildContext()))
+
+	if len(*nasInstanceIDs) == 0 && *configFile == "" && !*discovery {
+		logger.Error("at least one of --nifcloud.nas-instance-id, --nifcloud.config-file or --nifcloud.discovery must be set")
+		os.Exit(1)
+	}
+	if len(*nasInstanceIDs) > 0 && (*accessKeyID == "" || *secretAccessKey == "") {
+		logger.Error("--nifcloud.nas-instance-id requires --nifcloud.access-key-id and --nifcloud.secret-access-key")
+		os.Exit(1)
+	}
+	if *discovery && (*accessKeyID == "" || *secretAccessKey == "") {
+		logger.Error("--nifcloud.discovery requires --nifcloud.access-key-id and --nifcloud.secret-access-key")
+		os.Exit(1)
+	}
+
+	instances, err := resolveInstances(*nasInstanceIDs, *accessKeyID, *secretAccessKey, *region, *configFile)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	multiCollector := collector.NewMultiCollector(logger)
+	multiCollector.SetInstances(instances)
+
+	if *discovery {
+		discoverer := collector.NewDiscoverer(*accessKeyID, *secretAccessKey, *region)
+		go runDiscoveryLoop(discoverer, *region, *accessKeyID, *secretAccessKey, *discoveryInterval, instances, multiCollector, logger)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(*metricsPath, newHandler(!*disableExporterMetrics, *maxRequests, multiCollector, logger))
+	mux.HandleFunc("/probe", func(w http.ResponseWriter, r *http.Request) {
+		probeHandler(w, r, *maxRequests, instances, *accessKeyID, *secretAccessKey, *region, logger)
 	})
 
-	log.Infoln("Listening on", *listenAddress)
-	if err := http.ListenAndServe(*listenAddress, nil); err != nil {
-		log.Fatal(err)
+	landingPage, err := web.NewLandingPage(web.LandingConfig{
+		Name:        exporterName,
+		Description: "Prometheus exporter for NIFCLOUD NAS",
+		Version:     version.Info(),
+		Links: []web.LandingLinks{
+			{Address: *metricsPath, Text: "Metrics"},
+			{Address: "/probe?target=", Text: "Probe a NAS instance"},
+		},
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	mux.Handle("/", landingPage)
+
+	server := &http.Server{Handler: mux}
+	webFlags := &web.FlagConfig{
+		WebListenAddresses: &[]string{*listenAddress},
+		WebConfigFile:      webConfigFile,
+	}
+
+	go func() {
+		if err := web.ListenAndServe(server, webFlags, logger); err != nil && err != http.ErrServerClosed {
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
+
+	logger.Info("Shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+// resolveInstances merges instances named via --nifcloud.nas-instance-id with
+// any listed in --nifcloud.config-file.
+func resolveInstances(nasInstanceIDs []string, accessKeyID, secretAccessKey, region, configFile string) ([]collector.InstanceConfig, error) {
+	var instances []collector.InstanceConfig
+
+	for _, id := range nasInstanceIDs {
+		instances = append(instances, collector.InstanceConfig{
+			NASInstanceIdentifier: id,
+			Region:                region,
+			AccessKeyID:           accessKeyID,
+			SecretAccessKey:       secretAccessKey,
+		})
+	}
+
+	if configFile != "" {
+		config, err := collector.LoadConfig(configFile)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, config.Instances...)
+	}
+
+	return instances, nil
+}
+
+// runDiscoveryLoop periodically re-discovers the NAS instances present in
+// region and merges them with staticInstances (instances named via
+// --nifcloud.nas-instance-id or --nifcloud.config-file), so discovery adds
+// to the statically configured instances instead of replacing them.
+func runDiscoveryLoop(discoverer *collector.Discoverer, region, accessKeyID, secretAccessKey string,
+	interval time.Duration, staticInstances []collector.InstanceConfig, multiCollector *collector.MultiCollector, logger *slog.Logger) {
+	for {
+		ids, err := discoverer.Discover(context.Background())
+		if err != nil {
+			logger.Error("discovery failed", "region", region, "err", err)
+		} else {
+			seen := make(map[string]bool, len(staticInstances))
+			for _, instance := range staticInstances {
+				seen[instance.NASInstanceIdentifier] = true
+			}
+
+			instances := append([]collector.InstanceConfig{}, staticInstances...)
+			for _, id := range ids {
+				if seen[id] {
+					continue
+				}
+				instances = append(instances, collector.InstanceConfig{
+					NASInstanceIdentifier: id,
+					Region:                region,
+					AccessKeyID:           accessKeyID,
+					SecretAccessKey:       secretAccessKey,
+				})
+			}
+
+			logger.Info("discovered NAS instances", "region", region, "discovered", len(ids), "total", len(instances))
+			multiCollector.SetInstances(instances)
+		}
+
+		time.Sleep(interval)
 	}
 }
 
-func newHandler(includeExporterMetrics bool, maxRequests int,
-	nasInstanceIdentifier, accessKeyID, secretAccessKey, region string) http.Handler {
+// probeHandler implements the Prometheus "multi-target exporter" pattern for
+// the instance named by the `target` query parameter.
+func probeHandler(w http.ResponseWriter, r *http.Request, maxRequests int,
+	instances []collector.InstanceConfig, accessKeyID, secretAccessKey, region string, logger *slog.Logger) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		http.Error(w, "target parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	queryRegion := r.URL.Query().Get("region")
+	probeRegion := region
+	probeAccessKeyID, probeSecretAccessKey := accessKeyID, secretAccessKey
+
+	for _, instance := range instances {
+		if instance.NASInstanceIdentifier == target {
+			probeRegion = instance.Region
+			probeAccessKeyID = instance.AccessKeyID
+			probeSecretAccessKey = instance.SecretAccessKey
+			break
+		}
+	}
+
+	if queryRegion != "" {
+		probeRegion = queryRegion
+	}
+
+	if probeAccessKeyID == "" || probeSecretAccessKey == "" {
+		http.Error(w, fmt.Sprintf("no credentials configured for target %q", target), http.StatusBadRequest)
+		return
+	}
+
+	probeCollector := collector.NewNASCollector(target, probeAccessKeyID, probeSecretAccessKey, probeRegion, logger)
+	defer probeCollector.Close()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(probeCollector)
+
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		ErrorLog:            promErrorLogger{logger},
+		ErrorHandling:       promhttp.ContinueOnError,
+		MaxRequestsInFlight: maxRequests,
+	}).ServeHTTP(w, r)
+}
+
+func newHandler(includeExporterMetrics bool, maxRequests int, multiCollector *collector.MultiCollector, logger *slog.Logger) http.Handler {
 	exporterMetricsRegistry := prometheus.NewRegistry()
 
 	if includeExporterMetrics {
@@ -93,11 +281,11 @@ func newHandler(includeExporterMetrics bool, maxRequests int,
 
 	r := prometheus.NewRegistry()
 	r.MustRegister(version.NewCollector(exporterName))
-	r.Register(collector.NewNASCollector(nasInstanceIdentifier, accessKeyID, secretAccessKey, region))
+	r.MustRegister(multiCollector)
 	handler := promhttp.HandlerFor(
 		prometheus.Gatherers{exporterMetricsRegistry, r},
 		promhttp.HandlerOpts{
-			ErrorLog:            log.NewErrorLogger(),
+			ErrorLog:            promErrorLogger{logger},
 			ErrorHandling:       promhttp.ContinueOnError,
 			MaxRequestsInFlight: maxRequests,
 			Registry:            exporterMetricsRegistry,